@@ -0,0 +1,120 @@
+package csv_test
+
+import (
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"csv-go"
+)
+
+type sTimed struct {
+	Name      string
+	CreatedAt time.Time `csvFormat:"2006-01-02"`
+}
+
+func TestTimeFieldRoundTrip(t *testing.T) {
+	strBuf := strings.Builder{}
+	w := csv.NewWriter[*sTimed](&strBuf)
+	created := time.Date(2024, time.March, 5, 0, 0, 0, 0, time.UTC)
+	if err := w.WriteCSV([]*sTimed{{Name: "launch", CreatedAt: created}}); err != nil {
+		t.Fatal(err)
+	}
+	if strBuf.String() != "Name,CreatedAt\nlaunch,2024-03-05\n" {
+		t.Fatalf("unexpected output: %q", strBuf.String())
+	}
+
+	var res []sTimed
+	if err := csv.NewReader(strings.NewReader(strBuf.String())).ReadCSV(&res); err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != 1 || !res[0].CreatedAt.Equal(created) {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+}
+
+type sTimedPtr struct {
+	Name      string
+	CreatedAt *time.Time `csvFormat:"2006-01-02"`
+}
+
+func TestPointerTimeFieldRoundTrip(t *testing.T) {
+	strBuf := strings.Builder{}
+	w := csv.NewWriter[*sTimedPtr](&strBuf)
+	created := time.Date(2024, time.March, 5, 0, 0, 0, 0, time.UTC)
+	if err := w.WriteCSV([]*sTimedPtr{{Name: "launch", CreatedAt: &created}}); err != nil {
+		t.Fatal(err)
+	}
+	if strBuf.String() != "Name,CreatedAt\nlaunch,2024-03-05\n" {
+		t.Fatalf("unexpected output: %q", strBuf.String())
+	}
+
+	var res []sTimedPtr
+	if err := csv.NewReader(strings.NewReader(strBuf.String())).ReadCSV(&res); err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != 1 || res[0].CreatedAt == nil || !res[0].CreatedAt.Equal(created) {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+}
+
+type sIP struct {
+	Host string
+	Addr net.IP
+}
+
+func init() {
+	csv.RegisterType(
+		reflect.TypeOf(net.IP{}),
+		func(v reflect.Value) (string, error) {
+			return v.Interface().(net.IP).String(), nil
+		},
+		func(value string, v reflect.Value) error {
+			v.Set(reflect.ValueOf(net.ParseIP(value)))
+			return nil
+		},
+	)
+}
+
+func TestRegisteredTypeRoundTrip(t *testing.T) {
+	strBuf := strings.Builder{}
+	w := csv.NewWriter[*sIP](&strBuf)
+	if err := w.WriteCSV([]*sIP{{Host: "localhost", Addr: net.ParseIP("127.0.0.1")}}); err != nil {
+		t.Fatal(err)
+	}
+
+	var res []sIP
+	if err := csv.NewReader(strings.NewReader(strBuf.String())).ReadCSV(&res); err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != 1 || !res[0].Addr.Equal(net.ParseIP("127.0.0.1")) {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+}
+
+type sIPPtr struct {
+	Host string
+	Addr *net.IP
+}
+
+func TestPointerRegisteredTypeRoundTrip(t *testing.T) {
+	strBuf := strings.Builder{}
+	w := csv.NewWriter[*sIPPtr](&strBuf)
+	addr := net.ParseIP("127.0.0.1")
+	if err := w.WriteCSV([]*sIPPtr{{Host: "localhost", Addr: &addr}}); err != nil {
+		t.Fatal(err)
+	}
+	if strBuf.String() != "Host,Addr\nlocalhost,127.0.0.1\n" {
+		t.Fatalf("unexpected output: %q", strBuf.String())
+	}
+
+	var res []sIPPtr
+	if err := csv.NewReader(strings.NewReader(strBuf.String())).ReadCSV(&res); err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != 1 || res[0].Addr == nil || !res[0].Addr.Equal(addr) {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+}
@@ -0,0 +1,73 @@
+package csv_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"csv-go"
+)
+
+type sLenient struct {
+	Name string
+	Age  int
+}
+
+func TestErrorHandlerSkipsBadField(t *testing.T) {
+	r := csv.NewReader(strings.NewReader("Name,Age\nAlice,thirty\nBob,40\n"))
+
+	var skipped []csv.RowError
+	r.ErrorHandler = func(e *csv.RowError) error {
+		skipped = append(skipped, *e)
+		return nil
+	}
+
+	var res []sLenient
+	if err := r.ReadCSV(&res); err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != 2 {
+		t.Fatalf("expected 2 rows, but got %d", len(res))
+	}
+	if res[0].Age != 0 || res[1].Age != 40 {
+		t.Fatalf("unexpected ages: %+v", res)
+	}
+	if len(skipped) != 1 || skipped[0].Header != "Age" || skipped[0].RawValue != "thirty" {
+		t.Fatalf("unexpected skipped errors: %+v", skipped)
+	}
+}
+
+func TestWithoutErrorHandlerAbortsOnBadField(t *testing.T) {
+	r := csv.NewReader(strings.NewReader("Name,Age\nAlice,thirty\n"))
+	var res []sLenient
+	if err := r.ReadCSV(&res); err == nil {
+		t.Fatal("expected an error, got nil")
+	}
+}
+
+func TestFailIfDoubleHeaderNames(t *testing.T) {
+	r := csv.NewReader(strings.NewReader("Name,Name\nAlice,Smith\n"))
+	r.FailIfDoubleHeaderNames = true
+	var res []sLenient
+	err := r.ReadCSV(&res)
+	if err == nil {
+		t.Fatal("expected an error, got nil")
+	}
+}
+
+func TestFailIfUnmatchedStructTags(t *testing.T) {
+	r := csv.NewReader(strings.NewReader("Name\nAlice\n"))
+	r.FailIfUnmatchedStructTags = true
+	var res []sLenient
+	err := r.ReadCSV(&res)
+	var unmatched *csv.UnmatchedStructTagsError
+	if err == nil {
+		t.Fatal("expected an error, got nil")
+	}
+	if !errors.As(err, &unmatched) {
+		t.Fatalf("expected *UnmatchedStructTagsError, got %T: %v", err, err)
+	}
+	if len(unmatched.Missing) != 1 || unmatched.Missing[0] != "Age" {
+		t.Fatalf("unexpected missing fields: %+v", unmatched.Missing)
+	}
+}
@@ -0,0 +1,36 @@
+package csv_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"csv-go"
+)
+
+type money int // cents
+
+func (m *money) UnmarshalCSVField(header, value string) error {
+	var whole, cents int
+	if _, err := fmt.Sscanf(value, "%d.%d", &whole, &cents); err != nil {
+		return fmt.Errorf("%s: %w", header, err)
+	}
+	*m = money(whole*100 + cents)
+	return nil
+}
+
+type sMoney struct {
+	Item  string
+	Price money
+}
+
+func TestFieldUnmarshaler(t *testing.T) {
+	r := csv.NewReader(strings.NewReader("Item,Price\nWidget,19.99\n"))
+	var res []sMoney
+	if err := r.ReadCSV(&res); err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != 1 || res[0].Price != 1999 {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+}
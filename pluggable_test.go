@@ -0,0 +1,42 @@
+package csv_test
+
+import (
+	stdcsv "encoding/csv"
+	"strings"
+	"testing"
+
+	"csv-go"
+)
+
+type sPluggable struct {
+	Name string
+	Age  int
+}
+
+func TestReaderFromCustomCSVReader(t *testing.T) {
+	cr := stdcsv.NewReader(strings.NewReader("Name;Age\nAlice;30\n"))
+	cr.Comma = ';'
+
+	var res []sPluggable
+	if err := csv.NewReaderFromCSVReader(cr).ReadCSV(&res); err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != 1 || res[0].Name != "Alice" || res[0].Age != 30 {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+}
+
+func TestWriterFromCustomCSVWriter(t *testing.T) {
+	strBuf := strings.Builder{}
+	cw := stdcsv.NewWriter(&strBuf)
+	cw.Comma = ';'
+
+	w := csv.NewWriterFromCSVWriter[*sPluggable](cw)
+	if err := w.WriteCSV([]*sPluggable{{Name: "Alice", Age: 30}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if strBuf.String() != "Name;Age\nAlice;30\n" {
+		t.Fatalf("unexpected output: %q", strBuf.String())
+	}
+}
@@ -0,0 +1,62 @@
+package csv_test
+
+import (
+	stdcsv "encoding/csv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"csv-go"
+)
+
+type sQuoted struct {
+	Name  string
+	Notes string
+}
+
+func TestQuotingRoundTrip(t *testing.T) {
+	elems := []*sQuoted{
+		{Name: "Doe, Jane", Notes: `She said "hi"` + "\nand left"},
+		{Name: "plain", Notes: "no special characters"},
+	}
+
+	strBuf := strings.Builder{}
+	w := csv.NewWriter[*sQuoted](&strBuf)
+	if err := w.WriteCSV(elems); err != nil {
+		t.Fatal(err)
+	}
+
+	var res []sQuoted
+	if err := csv.NewReader(strings.NewReader(strBuf.String())).ReadCSV(&res); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(res) != len(elems) {
+		t.Fatalf("expected %d rows, but got %d", len(elems), len(res))
+	}
+	for i, elem := range elems {
+		assert.Equal(t, elem.Name, res[i].Name)
+		assert.Equal(t, elem.Notes, res[i].Notes)
+	}
+}
+
+func TestBareQuoteRejectedByDefault(t *testing.T) {
+	cr := stdcsv.NewReader(strings.NewReader("Name,Notes\nDoe,he said \"hi\"\n"))
+	r := csv.NewReaderFromCSVReader(cr)
+	var res []sQuoted
+	err := r.ReadCSV(&res)
+	var parseErr *stdcsv.ParseError
+	assert.ErrorAs(t, err, &parseErr)
+}
+
+func TestBareQuoteAllowedWithLazyQuotes(t *testing.T) {
+	cr := stdcsv.NewReader(strings.NewReader("Name,Notes\nDoe,he said \"hi\"\n"))
+	cr.LazyQuotes = true
+	r := csv.NewReaderFromCSVReader(cr)
+	var res []sQuoted
+	if err := r.ReadCSV(&res); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, `he said "hi"`, res[0].Notes)
+}
@@ -1,25 +1,112 @@
 package csv
 
 import (
-	"bufio"
+	stdcsv "encoding/csv"
 	"errors"
+	"fmt"
 	"io"
 	"reflect"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 )
 
+var timeType = reflect.TypeOf(time.Time{})
+
+type typeEncoder func(reflect.Value) (string, error)
+type typeDecoder func(string, reflect.Value) error
+
+var (
+	typeRegistryMu sync.RWMutex
+	typeRegistry   = map[reflect.Type]struct {
+		enc typeEncoder
+		dec typeDecoder
+	}{}
+)
+
+// RegisterType teaches setField and getString how to decode/encode a type
+// that isn't a built-in kind, time.Time, or a Parser/Stringer/
+// FieldUnmarshaler implementer - for instance net.IP, uuid.UUID, or
+// decimal.Decimal. It is also consulted for a pointer to t.
+func RegisterType(t reflect.Type, enc func(reflect.Value) (string, error), dec func(string, reflect.Value) error) {
+	typeRegistryMu.Lock()
+	defer typeRegistryMu.Unlock()
+	typeRegistry[t] = struct {
+		enc typeEncoder
+		dec typeDecoder
+	}{enc, dec}
+}
+
+func lookupType(t reflect.Type) (typeEncoder, typeDecoder, bool) {
+	typeRegistryMu.RLock()
+	defer typeRegistryMu.RUnlock()
+	entry, ok := typeRegistry[t]
+	return entry.enc, entry.dec, ok
+}
+
 var (
 	ErrCannotUnmarshalUnknownTypeWithoutHeader = errors.New("cannot unmarshal unknown type without header")
+	ErrDoubleHeaderName                        = errors.New("csv: duplicate header name")
 
 	errFieldNotFound = errors.New("field not found")
 )
 
+// RowError is passed to reader.ErrorHandler for every field-level decode
+// error. Line counts data rows starting at 1 (the header, if any, is not
+// counted); Column is the 1-based position of the field within the row.
+type RowError struct {
+	Line     int
+	Column   int
+	Header   string
+	RawValue string
+	Err      error
+}
+
+func (e *RowError) Error() string {
+	return fmt.Sprintf("csv: line %d, column %d (%q): %s", e.Line, e.Column, e.Header, e.Err)
+}
+
+func (e *RowError) Unwrap() error {
+	return e.Err
+}
+
+// UnmatchedStructTagsError is returned when FailIfUnmatchedStructTags is
+// set and one or more struct fields have no matching header cell.
+type UnmatchedStructTagsError struct {
+	Missing []string
+}
+
+func (e *UnmatchedStructTagsError) Error() string {
+	return fmt.Sprintf("csv: unmatched struct tags: %s", strings.Join(e.Missing, ", "))
+}
+
+// CSVReader is the minimal surface reader needs from an underlying CSV
+// tokenizer: one record at a time, or all of them at once. *encoding/csv.Reader
+// satisfies it, which is what NewReader wires up by default; supplying your
+// own implementation (gzip-aware, tab-delimited, concurrent-safe, ...) lets
+// you reuse all the struct-mapping logic below without touching it.
+type CSVReader interface {
+	Read() ([]string, error)
+	ReadAll() ([][]string, error)
+}
+
 type reader struct {
-	reader     *bufio.Reader
+	csvReader  CSVReader
 	WithHeader bool
-	Comma      rune
-	UseCRLF    bool
+
+	// FailIfUnmatchedStructTags, if true, makes decoding fail if one of
+	// dst's csv tags or field names has no matching header cell.
+	FailIfUnmatchedStructTags bool
+	// FailIfDoubleHeaderNames, if true, rejects a header row that repeats
+	// the same cell more than once.
+	FailIfDoubleHeaderNames bool
+	// ErrorHandler, if set, is invoked with a *RowError for every
+	// field-level decode error instead of aborting the whole read.
+	// Returning nil from it skips the field and continues decoding the
+	// row; returning an error aborts the read with that error. If nil,
+	// the first field-level error aborts the read directly.
+	ErrorHandler func(*RowError) error
 }
 
 type Parser interface {
@@ -35,92 +122,213 @@ type Unmarshaler interface {
 	UnmarshalCSVWithHeader(values, names []string) error
 }
 
-// Creates a new reader from an io.Reader. Default separator is ',',
-// default UseCRLF is false, and default WithHeader is true.
+// FieldUnmarshaler lets a type own its own per-field parsing, with access
+// to the header it was read from. setField prefers it over Parser and the
+// built-in kind switch, and it also applies when a pointer field's pointee
+// implements it.
+type FieldUnmarshaler interface {
+	UnmarshalCSVField(header, value string) error
+}
+
+var fieldUnmarshalerType = reflect.TypeOf((*FieldUnmarshaler)(nil)).Elem()
+
+// TagSeparator splits a csv tag into multiple header aliases, e.g.
+// `csv:"Client Name|client_name|name"` matches any of the three. Defaults
+// to "|"; change it if your headers may themselves contain "|".
+var TagSeparator = "|"
+
+// NewReader creates a new reader from an io.Reader, wiring up an
+// encoding/csv.Reader with Comma ',' as the underlying CSVReader. Default
+// WithHeader is true. Comma, LazyQuotes, and other *encoding/csv.Reader
+// knobs are no longer settable on reader directly - build your own
+// *encoding/csv.Reader with those fields set and use
+// NewReaderFromCSVReader instead.
 func NewReader(r io.Reader) *reader {
+	cr := stdcsv.NewReader(r)
+	cr.Comma = ','
+	return NewReaderFromCSVReader(cr)
+}
+
+// NewReaderFromCSVReader wraps an existing CSVReader - for instance a
+// customized *encoding/csv.Reader, or any structurally compatible type -
+// so its records can be decoded into structs. Default WithHeader is true.
+func NewReaderFromCSVReader(cr CSVReader) *reader {
 	return &reader{
-		reader:     bufio.NewReader(r),
-		Comma:      ',',
+		csvReader:  cr,
 		WithHeader: true,
 	}
 }
 
 func (r reader) ReadCSV(arr interface{}) error {
-	const EOL = '\n'
-	var attrs []string
 	sliceType := reflect.TypeOf(arr).Elem()
-	result := reflect.MakeSlice(sliceType, 0, 20)
 	elemType := sliceType.Elem()
-	if r.WithHeader {
+	result := reflect.MakeSlice(sliceType, 0, 20)
 
-		header, err := r.reader.ReadString(EOL)
-		if err != nil {
+	it := r.Rows()
+	for it.Next() {
+		item := reflect.New(elemType)
+		if err := it.Scan(item.Interface()); err != nil {
 			return err
 		}
-		if r.UseCRLF {
-			header = strings.TrimSuffix(header, "\r\n")
-		} else {
-			header = strings.TrimSuffix(header, "\n")
+		result = reflect.Append(result, item.Elem())
+	}
+	if err := it.Err(); err != nil {
+		return err
+	}
+	reflect.ValueOf(arr).Elem().Set(result)
+	return nil
+}
+
+// RowIter streams records out of a reader one at a time, decoding each into
+// a caller-provided destination without ever buffering the whole file.
+// Obtain one with reader.Rows.
+type RowIter struct {
+	r             reader
+	attrs         []string
+	values        []string
+	started       bool
+	headerChecked bool
+	line          int
+	err           error
+}
+
+// Rows returns a RowIter over r. The header, if any, is read on the first
+// call to Next.
+func (r reader) Rows() *RowIter {
+	return &RowIter{r: r}
+}
+
+// Next reads the next record, making it available to Scan. It returns
+// false once the underlying reader is exhausted or an error occurs; call
+// Err to distinguish the two.
+func (it *RowIter) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	if !it.started {
+		it.started = true
+		if it.r.WithHeader {
+			attrs, err := it.r.csvReader.Read()
+			if err != nil {
+				it.err = err
+				return false
+			}
+			if it.r.FailIfDoubleHeaderNames {
+				seen := make(map[string]bool, len(attrs))
+				for _, attr := range attrs {
+					if seen[attr] {
+						it.err = fmt.Errorf("%w: %q", ErrDoubleHeaderName, attr)
+						return false
+					}
+					seen[attr] = true
+				}
+			}
+			it.attrs = attrs
 		}
-		header = strings.TrimSuffix(header, string(r.Comma))
-		attrs = strings.Split(header, string(r.Comma))
 	}
-	for {
-		line, err := r.reader.ReadString(EOL)
-		if err != nil {
-			if errors.Is(err, io.EOF) {
-				break
-			} else {
+	values, err := it.r.csvReader.Read()
+	if err != nil {
+		if !errors.Is(err, io.EOF) {
+			it.err = err
+		}
+		return false
+	}
+	it.values = values
+	it.line++
+	return true
+}
+
+// Scan decodes the most recent record read by Next into dst, which must be
+// a pointer to the element type. It uses the same Unmarshaler/
+// UnmarshalerWithoutHeader/reflection dispatch as ReadCSV.
+func (it *RowIter) Scan(dst any) error {
+	item := reflect.ValueOf(dst)
+	if !it.r.WithHeader {
+		if unm, ok := item.Interface().(UnmarshalerWithoutHeader); ok {
+			if err := unm.UnmarshalCSV(it.values); err != nil {
 				return err
 			}
-		}
-		if r.UseCRLF {
-			line = strings.TrimSuffix(line, "\r\n")
 		} else {
-			line = strings.TrimSuffix(line, "\n")
+			return ErrCannotUnmarshalUnknownTypeWithoutHeader
 		}
-		line = strings.TrimSuffix(line, string(r.Comma))
-		values := strings.Split(line, string(r.Comma))
-		item := reflect.New(elemType)
-		if !r.WithHeader {
-			if unm, ok := item.Interface().(UnmarshalerWithoutHeader); ok {
-				if err := unm.UnmarshalCSV(values); err != nil {
-					return err
-				}
-			} else {
-				return ErrCannotUnmarshalUnknownTypeWithoutHeader
+	}
+	if unm, ok := item.Interface().(Unmarshaler); ok {
+		if err := unm.UnmarshalCSVWithHeader(it.values, it.attrs); err != nil {
+			return err
+		}
+	} else {
+		fields := reflect.VisibleFields(item.Elem().Type())
+		if it.r.WithHeader && it.r.FailIfUnmatchedStructTags && !it.headerChecked {
+			it.headerChecked = true
+			if missing := unmatchedFields(fields, it.attrs); len(missing) > 0 {
+				return &UnmatchedStructTagsError{Missing: missing}
 			}
 		}
-		if unm, ok := item.Interface().(Unmarshaler); ok {
-			if err := unm.UnmarshalCSVWithHeader(values, attrs); err != nil {
+		for i, attr := range it.attrs {
+			field, err := getField(fields, attr)
+			if err != nil {
+				if errors.Is(err, errFieldNotFound) {
+					continue
+				}
 				return err
 			}
-		} else {
-			fields := reflect.VisibleFields(elemType)
-			for i, attr := range attrs {
-				field, err := getField(fields, attr)
-				if err != nil {
-					if errors.Is(err, errFieldNotFound) {
-						continue
-					}
+			if err := setField(&item, field, attr, it.values[i]); err != nil {
+				if it.r.ErrorHandler == nil {
 					return err
 				}
-				if err := setField(&item, field, values[i]); err != nil {
-					return err
+				if herr := it.r.ErrorHandler(&RowError{
+					Line:     it.line,
+					Column:   i + 1,
+					Header:   attr,
+					RawValue: it.values[i],
+					Err:      err,
+				}); herr != nil {
+					return herr
 				}
 			}
 		}
-		result = reflect.Append(result, item.Elem())
 	}
-	reflect.ValueOf(arr).Elem().Set(result)
 	return nil
 }
 
+// Err returns the first non-io.EOF error encountered by Next, if any.
+func (it *RowIter) Err() error {
+	return it.err
+}
+
+// ForEach streams rows from r, decoding each into a freshly allocated value
+// of prototype's type (or, if prototype is a pointer, of the type it points
+// to) and invoking fn with a pointer to that value. It never retains more
+// than one decoded row at a time, making it suitable for multi-GB files.
+func (r reader) ForEach(fn func(any) error, prototype any) error {
+	elemType := reflect.TypeOf(prototype)
+	if elemType.Kind() == reflect.Pointer {
+		elemType = elemType.Elem()
+	}
+	it := r.Rows()
+	for it.Next() {
+		item := reflect.New(elemType)
+		if err := it.Scan(item.Interface()); err != nil {
+			return err
+		}
+		if err := fn(item.Interface()); err != nil {
+			return err
+		}
+	}
+	return it.Err()
+}
+
 func getField(fields []reflect.StructField, attr string) (int, error) {
-	// first look for tag
+	// first look for tag, which may list several aliases separated by TagSeparator
 	for i, field := range fields {
-		if field.Tag.Get("csv") == attr {
-			return i, nil
+		tag, ok := field.Tag.Lookup("csv")
+		if !ok || tag == "-" {
+			continue
+		}
+		for _, alias := range strings.Split(tag, TagSeparator) {
+			if alias == attr {
+				return i, nil
+			}
 		}
 	}
 	// then look for name
@@ -135,7 +343,101 @@ func getField(fields []reflect.StructField, attr string) (int, error) {
 	return -1, errFieldNotFound
 }
 
-func setField(item *reflect.Value, field int, value string) error {
+// unmatchedFields returns, for every exported, non-"-" field, the first
+// alias of its tag (or its name, if untagged) when no header cell in attrs
+// matches it.
+func unmatchedFields(fields []reflect.StructField, attrs []string) []string {
+	var missing []string
+	for _, field := range fields {
+		if !field.IsExported() {
+			continue
+		}
+		tag, hasTag := field.Tag.Lookup("csv")
+		if tag == "-" {
+			continue
+		}
+		label := field.Name
+		var aliases []string
+		if hasTag {
+			aliases = strings.Split(tag, TagSeparator)
+			label = aliases[0]
+		}
+		found := false
+		for _, attr := range attrs {
+			if hasTag {
+				for _, alias := range aliases {
+					if attr == alias {
+						found = true
+						break
+					}
+				}
+			} else if strings.EqualFold(attr, field.Name) {
+				found = true
+			}
+			if found {
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, label)
+		}
+	}
+	return missing
+}
+
+func setField(item *reflect.Value, field int, header, value string) error {
+	fv := item.Elem().Field(field)
+	ft := fv.Type()
+	if ft.Kind() != reflect.Pointer {
+		ft = reflect.PointerTo(ft)
+	}
+	if ft.Implements(fieldUnmarshalerType) {
+		target := fv
+		if fv.Kind() != reflect.Pointer {
+			target = fv.Addr()
+		} else if fv.IsNil() {
+			fv.Set(reflect.New(fv.Type().Elem()))
+		}
+		return target.Interface().(FieldUnmarshaler).UnmarshalCSVField(header, value)
+	}
+
+	targetType := fv.Type()
+	isPointerTarget := targetType.Kind() == reflect.Pointer
+	underlyingType := targetType
+	if isPointerTarget {
+		underlyingType = targetType.Elem()
+	}
+
+	if underlyingType == timeType {
+		layout := item.Elem().Type().Field(field).Tag.Get("csvFormat")
+		if layout == "" {
+			layout = time.RFC3339
+		}
+		t, err := time.Parse(layout, strings.TrimSpace(value))
+		if err != nil {
+			return err
+		}
+		if isPointerTarget {
+			fv.Set(reflect.ValueOf(&t))
+		} else {
+			fv.Set(reflect.ValueOf(t))
+		}
+		return nil
+	}
+
+	if _, dec, ok := lookupType(underlyingType); ok {
+		if isPointerTarget {
+			newVal := reflect.New(underlyingType)
+			if err := dec(value, newVal.Elem()); err != nil {
+				return err
+			}
+			fv.Set(newVal)
+		} else if err := dec(value, fv); err != nil {
+			return err
+		}
+		return nil
+	}
+
 	if parser, ok := item.Elem().Field(field).Interface().(Parser); ok {
 		newItem, err := parser.Parse(value)
 		if err != nil {
@@ -210,11 +512,10 @@ func setField(item *reflect.Value, field int, value string) error {
 			}
 		}
 	case reflect.String:
-		val := strings.TrimPrefix(strings.TrimSuffix(value, `"`), `"`)
 		if !isPointer {
-			item.Elem().Field(field).SetString(val)
+			item.Elem().Field(field).SetString(value)
 		} else {
-			item.Elem().Field(field).Set(reflect.ValueOf(val))
+			item.Elem().Field(field).Set(reflect.ValueOf(value))
 		}
 	}
 	return nil
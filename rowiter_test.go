@@ -0,0 +1,57 @@
+package csv_test
+
+import (
+	"strings"
+	"testing"
+
+	"csv-go"
+)
+
+type sIter struct {
+	Name string
+	Age  int
+}
+
+func TestRowIter(t *testing.T) {
+	r := csv.NewReader(strings.NewReader("Name,Age\nAlice,30\nBob,40\n"))
+	it := r.Rows()
+
+	var got []sIter
+	for it.Next() {
+		var row sIter
+		if err := it.Scan(&row); err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, row)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(got) != 2 {
+		t.Fatalf("expected 2 rows, but got %d", len(got))
+	}
+	if got[0].Name != "Alice" || got[0].Age != 30 {
+		t.Fatalf("unexpected first row: %+v", got[0])
+	}
+	if got[1].Name != "Bob" || got[1].Age != 40 {
+		t.Fatalf("unexpected second row: %+v", got[1])
+	}
+}
+
+func TestForEach(t *testing.T) {
+	r := csv.NewReader(strings.NewReader("Name,Age\nAlice,30\nBob,40\n"))
+
+	var got []sIter
+	err := r.ForEach(func(row any) error {
+		got = append(got, *row.(*sIter))
+		return nil
+	}, sIter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(got) != 2 {
+		t.Fatalf("expected 2 rows, but got %d", len(got))
+	}
+}
@@ -0,0 +1,61 @@
+package csv
+
+import (
+	"io"
+	"sync"
+)
+
+// SafeWriter wraps a writer for use by multiple goroutines producing rows
+// concurrently - a fan-in pipeline or a worker pool sink. The header is
+// written lazily on the first row, guarded by a sync.Once, so producers
+// don't need to coordinate startup.
+type SafeWriter[T any] struct {
+	mu   sync.Mutex
+	w    *writer[T]
+	once sync.Once
+}
+
+// NewSafeWriter creates a new SafeWriter from an io.Writer, with the same
+// defaults as NewWriter.
+func NewSafeWriter[T any](w io.Writer) *SafeWriter[T] {
+	return &SafeWriter[T]{w: NewWriter[T](w)}
+}
+
+// WriteRow writes a single row, safe for concurrent use by many goroutines.
+func (sw *SafeWriter[T]) WriteRow(row T) error {
+	return sw.WriteRows([]T{row})
+}
+
+// WriteRows writes rows as a batch, safe for concurrent use by many
+// goroutines.
+func (sw *SafeWriter[T]) WriteRows(rows []T) error {
+	sw.mu.Lock()
+	defer sw.mu.Unlock()
+
+	if sw.w.WithHeader && len(rows) > 0 {
+		var headerErr error
+		sw.once.Do(func() {
+			headerErr = sw.w.writeHeader(rows)
+		})
+		if headerErr != nil {
+			return headerErr
+		}
+	}
+
+	for _, row := range rows {
+		if err := sw.w.writeElem(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Flush flushes the underlying CSVWriter. It is only called here, not per
+// row, to avoid lock contention between producers.
+func (sw *SafeWriter[T]) Flush() error {
+	sw.mu.Lock()
+	defer sw.mu.Unlock()
+
+	sw.w.csvWriter.Flush()
+	return sw.w.csvWriter.Error()
+}
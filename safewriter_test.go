@@ -0,0 +1,48 @@
+package csv_test
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	"csv-go"
+)
+
+type sSafe struct {
+	Worker int
+	Seq    int
+}
+
+func TestSafeWriterConcurrentWriteRow(t *testing.T) {
+	strBuf := strings.Builder{}
+	sw := csv.NewSafeWriter[sSafe](&strBuf)
+
+	const workers = 8
+	const rowsPerWorker = 20
+
+	var wg sync.WaitGroup
+	for worker := 0; worker < workers; worker++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			for seq := 0; seq < rowsPerWorker; seq++ {
+				if err := sw.WriteRow(sSafe{Worker: worker, Seq: seq}); err != nil {
+					t.Error(err)
+				}
+			}
+		}(worker)
+	}
+	wg.Wait()
+
+	if err := sw.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimRight(strBuf.String(), "\n"), "\n")
+	if len(lines) != workers*rowsPerWorker+1 {
+		t.Fatalf("expected %d lines, got %d", workers*rowsPerWorker+1, len(lines))
+	}
+	if lines[0] != "Worker,Seq" {
+		t.Fatalf("unexpected header: %q", lines[0])
+	}
+}
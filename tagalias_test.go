@@ -0,0 +1,33 @@
+package csv_test
+
+import (
+	"strings"
+	"testing"
+
+	"csv-go"
+)
+
+type sAliased struct {
+	ClientName string `csv:"Client Name|client_name|name"`
+}
+
+func TestGetFieldMatchesAnyAlias(t *testing.T) {
+	var res []sAliased
+	if err := csv.NewReader(strings.NewReader("client_name\nAcme Corp\n")).ReadCSV(&res); err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != 1 || res[0].ClientName != "Acme Corp" {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+}
+
+func TestWriterEmitsFirstAliasAsHeader(t *testing.T) {
+	strBuf := strings.Builder{}
+	w := csv.NewWriter[*sAliased](&strBuf)
+	if err := w.WriteCSV([]*sAliased{{ClientName: "Acme Corp"}}); err != nil {
+		t.Fatal(err)
+	}
+	if strBuf.String() != "Client Name\nAcme Corp\n" {
+		t.Fatalf("unexpected output: %q", strBuf.String())
+	}
+}
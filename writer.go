@@ -1,25 +1,34 @@
 package csv
 
 import (
-	"bufio"
+	stdcsv "encoding/csv"
 	"errors"
 	"fmt"
 	"io"
 	"reflect"
 	"strings"
+	"time"
 )
 
 var (
-	ErrNoCommasAllowedInHeader = errors.New("no commas allowed in header")
-	ErrNoCommasAllowedInBody   = errors.New("no commas allowed in body")
-	ErrEmptySlice              = errors.New("empty slice was received")
+	ErrEmptySlice = errors.New("empty slice was received")
 )
 
+// CSVWriter is the minimal surface writer needs from an underlying CSV
+// formatter: write one record, flush, and report the last error.
+// *encoding/csv.Writer satisfies it, which is what NewWriter wires up by
+// default; supplying your own implementation (gzip-aware, tab-delimited,
+// concurrent-safe, ...) lets you reuse all the struct-mapping logic below
+// without touching it.
+type CSVWriter interface {
+	Write([]string) error
+	Flush()
+	Error() error
+}
+
 type writer[T any] struct {
-	writer     *bufio.Writer
+	csvWriter  CSVWriter
 	WithHeader bool
-	Comma      rune
-	UseCRLF    bool
 }
 
 type Stringer interface {
@@ -28,72 +37,56 @@ type Stringer interface {
 
 type HeaderMarshaler interface {
 	// Header should return a slice of strings, which will be used as the header of the CSV file.
-	// No comma allowed, comma being what you set in the writer (by default ',').
 	Header() []string
 }
 
 type BodyMarshaler interface {
 	// MarshalCSV should return a slice of strings, where each string is a value.
-	// No Comma is allowed, comma being what you set in the writer (by default ',').
 	MarshalCSV() []string
 }
 
-// Creates a new writer from an io.Writer. Default separator is ',', default
-// UseCRLF is false, and default WithHeader is true.
+// NewWriter creates a new writer from an io.Writer, wiring up an
+// encoding/csv.Writer with Comma ',' as the underlying CSVWriter. Default
+// WithHeader is true. Comma, UseCRLF, and other *encoding/csv.Writer
+// knobs are no longer settable on writer directly - build your own
+// *encoding/csv.Writer with those fields set and use
+// NewWriterFromCSVWriter instead.
 func NewWriter[T any](w io.Writer) *writer[T] {
+	cw := stdcsv.NewWriter(w)
+	cw.Comma = ','
+	return NewWriterFromCSVWriter[T](cw)
+}
+
+// NewWriterFromCSVWriter wraps an existing CSVWriter - for instance a
+// customized *encoding/csv.Writer, or any structurally compatible type -
+// so struct values can be formatted onto it. Default WithHeader is true.
+func NewWriterFromCSVWriter[T any](cw CSVWriter) *writer[T] {
 	return &writer[T]{
-		writer:     bufio.NewWriter(w),
-		Comma:      ',',
+		csvWriter:  cw,
 		WithHeader: true,
 	}
 }
 
 func (w writer[T]) WriteCSV(arr []T) error {
-	var EOL string
-	if w.UseCRLF {
-		EOL = "\r\n"
-	} else {
-		EOL = "\n"
-	}
-
 	if w.WithHeader {
 		if err := w.writeHeader(arr); err != nil {
 			return err
 		}
-		if _, err := w.writer.WriteString(EOL); err != nil {
-			return err
-		}
 	}
 
 	for _, elem := range arr {
 		if err := w.writeElem(elem); err != nil {
 			return err
 		}
-		if _, err := w.writer.WriteString(EOL); err != nil {
-			return err
-		}
 	}
 
-	w.writer.Flush()
-	return nil
+	w.csvWriter.Flush()
+	return w.csvWriter.Error()
 }
 
 func (w writer[T]) writeElem(e T) error {
 	if mar, ok := any(e).(BodyMarshaler); ok {
-		for i, value := range mar.MarshalCSV() {
-			if i > 0 {
-				if _, err := w.writer.WriteRune(w.Comma); err != nil {
-					return err
-				}
-			}
-			if strings.ContainsRune(value, w.Comma) {
-				return ErrNoCommasAllowedInBody
-			}
-			if _, err := w.writer.WriteString(value); err != nil {
-				return err
-			}
-		}
-		return nil
+		return w.csvWriter.Write(mar.MarshalCSV())
 	}
 
 	elemType := reflect.TypeOf(e)
@@ -103,35 +96,56 @@ func (w writer[T]) writeElem(e T) error {
 		elem = elem.Elem()
 	}
 	fields := reflect.VisibleFields(elemType)
-	i := 0
+	values := make([]string, 0, len(fields))
 	for _, field := range fields {
-		if strings.ContainsRune(field.Name, w.Comma) {
-			return ErrNoCommasAllowedInBody
-		}
 		if !field.IsExported() {
 			continue
 		}
 		if field.Tag.Get("csv") == "-" {
 			continue
 		}
-		if i > 0 {
-			if _, err := w.writer.WriteRune(w.Comma); err != nil {
-				return err
-			}
-		}
-		str, err := getString(elem.FieldByName(field.Name))
+		str, err := getString(elem.FieldByName(field.Name), field)
 		if err != nil {
 			return err
 		}
-		if _, err := w.writer.WriteString(str); err != nil {
-			return err
-		}
-		i++
+		values = append(values, str)
 	}
-	return nil
+	return w.csvWriter.Write(values)
 }
 
-func getString(v reflect.Value) (string, error) {
+func getString(v reflect.Value, field reflect.StructField) (string, error) {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return "", nil
+		}
+		// timeType and the registry dispatch on the dereferenced type take
+		// priority, same as setField: they need the *value*, not the
+		// pointer, to apply csvFormat/the registered encoder. Only once
+		// those don't apply do we give the pointer itself a chance at
+		// Stringer, so a pointer-receiver String() method on a *T field is
+		// honored instead of falling through to T's promoted String().
+		underlying := v.Elem()
+		if underlying.Type() == timeType {
+			return getString(underlying, field)
+		}
+		if _, _, ok := lookupType(underlying.Type()); ok {
+			return getString(underlying, field)
+		}
+		if stringer, ok := v.Interface().(Stringer); ok {
+			return stringer.String(), nil
+		}
+		return getString(underlying, field)
+	}
+	if v.Type() == timeType {
+		layout := field.Tag.Get("csvFormat")
+		if layout == "" {
+			layout = time.RFC3339
+		}
+		return v.Interface().(time.Time).Format(layout), nil
+	}
+	if enc, _, ok := lookupType(v.Type()); ok {
+		return enc(v)
+	}
 	// if v has String method, will use it to get the string
 	if stringer, ok := v.Interface().(Stringer); ok {
 		return stringer.String(), nil
@@ -147,8 +161,6 @@ func getString(v reflect.Value) (string, error) {
 		return fmt.Sprint(v.Float()), nil
 	case reflect.Bool:
 		return fmt.Sprint(v.Bool()), nil
-	case reflect.Pointer:
-		return getString(v.Elem())
 	}
 	return "", errors.New("unsupported type")
 }
@@ -159,53 +171,29 @@ func (w writer[T]) writeHeader(arr []T) error {
 	}
 
 	if mar, ok := any(arr[0]).(HeaderMarshaler); ok {
-		headerNames := mar.Header()
-		for i, name := range headerNames {
-			if i > 0 {
-				if _, err := w.writer.WriteRune(w.Comma); err != nil {
-					return err
-				}
-			}
-			if strings.ContainsRune(name, w.Comma) {
-				return ErrNoCommasAllowedInHeader
-			}
-			if _, err := w.writer.WriteString(name); err != nil {
-				return err
-			}
+		return w.csvWriter.Write(mar.Header())
+	}
 
+	elem := reflect.ValueOf(arr[0])
+	for elem.Kind() == reflect.Pointer {
+		elem = elem.Elem()
+	}
+	var headerNames []string
+	for _, field := range reflect.VisibleFields(elem.Type()) {
+		if field.Anonymous || !field.IsExported() {
+			continue
 		}
-	} else {
-		elem := reflect.ValueOf(arr[0])
-		for elem.Kind() == reflect.Pointer {
-			elem = elem.Elem()
-		}
-		i := 0
-		for _, field := range reflect.VisibleFields(elem.Type()) {
-			if field.Anonymous || !field.IsExported() {
+		headerName := field.Name
+		if tag, ok := field.Tag.Lookup("csv"); ok {
+			if tag == "-" {
 				continue
 			}
-			headerName := field.Name
-			if tag, ok := field.Tag.Lookup("csv"); ok {
-				if tag == "-" {
-					continue
-				}
-				if strings.ContainsRune(tag, w.Comma) {
-					return ErrNoCommasAllowedInHeader
-				}
-				headerName = tag
-			}
-			if i > 0 {
-				if _, err := w.writer.WriteRune(w.Comma); err != nil {
-					return err
-				}
-			}
-			if _, err := w.writer.WriteString(headerName); err != nil {
-				return err
-			}
-			i++
+			// the first alias is used as the emitted header name
+			headerName, _, _ = strings.Cut(tag, TagSeparator)
 		}
+		headerNames = append(headerNames, headerName)
 	}
-	return nil
+	return w.csvWriter.Write(headerNames)
 }
 
 // WriteCSVElems is the same as WriteCSV, but you do not have to pass a slice. This is useful
@@ -130,6 +130,37 @@ func TestStructWithTags(t *testing.T) {
 	)
 }
 
+type ptrStr struct {
+	val string
+}
+
+// String has a pointer receiver, so only *ptrStr implements Stringer.
+func (p *ptrStr) String() string {
+	return "wrapped:" + p.val
+}
+
+func TestStructWithPointerStringerField(t *testing.T) {
+	type s struct {
+		Name  string
+		Extra *ptrStr
+	}
+	strBuf := strings.Builder{}
+	w := csv.NewWriter[*s](&strBuf)
+	elems := []*s{{
+		Name:  "row",
+		Extra: &ptrStr{val: "x"},
+	}}
+
+	w.WriteCSV(elems)
+	result := strBuf.String()
+
+	assert.Equal(t,
+		"Name,Extra\n"+
+			"row,wrapped:x\n",
+		result,
+	)
+}
+
 // TODO: WIP
 // func TestMap(t *testing.T) {
 // 	strBuf := strings.Builder{}